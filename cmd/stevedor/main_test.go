@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/thebsdbox/stevedor/pkg/stevedor"
+)
+
+func TestNetworkFlagSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    stevedor.NetworkSpec
+		wantErr bool
+	}{
+		{
+			name:  "name only defaults to vmxnet3",
+			value: "VM Network",
+			want:  stevedor.NetworkSpec{Name: "VM Network", AdapterType: "vmxnet3"},
+		},
+		{
+			name:  "name and adapter type",
+			value: "VM Network:e1000",
+			want:  stevedor.NetworkSpec{Name: "VM Network", AdapterType: "e1000"},
+		},
+		{
+			name:  "name, adapter type and mac",
+			value: "VM Network:e1000e:00:50:56:aa:bb:cc",
+			want:  stevedor.NetworkSpec{Name: "VM Network", AdapterType: "e1000e", MAC: "00:50:56:aa:bb:cc"},
+		},
+		{
+			name:  "empty adapter type falls back to default",
+			value: "VM Network::00:50:56:aa:bb:cc",
+			want:  stevedor.NetworkSpec{Name: "VM Network", AdapterType: "vmxnet3", MAC: "00:50:56:aa:bb:cc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n networkFlag
+			err := n.Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(n) != 1 || n[0] != tt.want {
+				t.Fatalf("Set(%q) = %+v, want %+v", tt.value, n, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiskFlagSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    stevedor.DiskSpec
+		wantErr bool
+	}{
+		{
+			name:  "name and size defaults to lazy",
+			value: "data:102400",
+			want:  stevedor.DiskSpec{Name: "data", SizeMB: 102400, Provisioning: "lazy"},
+		},
+		{
+			name:  "name, size and provisioning",
+			value: "data:102400:thin",
+			want:  stevedor.DiskSpec{Name: "data", SizeMB: 102400, Provisioning: "thin"},
+		},
+		{
+			name:    "missing size",
+			value:   "data",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric size",
+			value:   "data:notanumber",
+			wantErr: true,
+		},
+		{
+			name:    "zero size rejected",
+			value:   "data:0",
+			wantErr: true,
+		},
+		{
+			name:    "negative size rejected",
+			value:   "data:-5",
+			wantErr: true,
+		},
+		{
+			name:    "invalid provisioning rejected",
+			value:   "data:1024:bogus",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d diskFlag
+			err := d.Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(d) != 1 || d[0] != tt.want {
+				t.Fatalf("Set(%q) = %+v, want %+v", tt.value, d, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuestInfoFlagSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantKey string
+		wantVal string
+		wantErr bool
+	}{
+		{name: "key=value", value: "hostname=web01", wantKey: "hostname", wantVal: "web01"},
+		{name: "value contains equals", value: "args=foo=bar", wantKey: "args", wantVal: "foo=bar"},
+		{name: "missing equals rejected", value: "hostname", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g guestInfoFlag
+			err := g.Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if g[tt.wantKey] != tt.wantVal {
+				t.Fatalf("Set(%q) = %+v, want [%s]=%s", tt.value, g, tt.wantKey, tt.wantVal)
+			}
+		})
+	}
+}
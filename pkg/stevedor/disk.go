@@ -0,0 +1,131 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DiskSpec describes a single persistent disk to attach: its name (the
+// resulting VMDK is named "<name>.vmdk"), its size, and its provisioning
+// mode (eager, thin or lazy).
+type DiskSpec struct {
+	Name         string
+	SizeMB       int64
+	Provisioning string
+}
+
+func validControllerType(controller string) bool {
+	switch controller {
+	case "ide", "lsilogic", "lsilogic-sas", "buslogic", "pvscsi":
+		return true
+	}
+	return false
+}
+
+// findOrCreateController returns a disk controller for vm. For "ide" it
+// returns vm's existing IDE controller and never adds one. Otherwise it
+// returns vm's existing SCSI controller, whatever its type, if it has one,
+// and only adds a new controllerType SCSI controller when it has none.
+func findOrCreateController(ctx context.Context, vm *object.VirtualMachine, controllerType string) (types.BaseVirtualController, error) {
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+
+	if controllerType == "ide" {
+		return devices.FindIDEController("")
+	}
+
+	if c, err := devices.FindDiskController("scsi"); err == nil {
+		return c, nil
+	}
+
+	scsi, err := object.SCSIControllerTypes().CreateSCSIController(controllerType)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s controller: %w", controllerType, err)
+	}
+
+	if err := vm.AddDevice(ctx, scsi); err != nil {
+		return nil, fmt.Errorf("attaching %s controller: %w", controllerType, err)
+	}
+
+	devices, err = vm.Device(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+
+	return devices.FindDiskController("scsi")
+}
+
+// AttachDisk creates (or, if the VMDK already exists on dss, re-attaches) a
+// persistent disk described by spec under vmName's folder on dss.
+func (c *Client) AttachDisk(ctx context.Context, vm *object.VirtualMachine, dss *object.Datastore, vmName, controllerType string, spec DiskSpec) error {
+	controller, err := findOrCreateController(ctx, vm, controllerType)
+	if err != nil {
+		return err
+	}
+
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	dsFile := fmt.Sprintf("%s/%s.vmdk", vmName, spec.Name)
+	disk := devices.CreateDisk(controller, dss.Reference(), dss.Path(dsFile))
+	if spec.SizeMB > 0 {
+		disk.CapacityInKB = spec.SizeMB * 1024
+	}
+
+	if backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+		switch spec.Provisioning {
+		case "eager":
+			backing.ThinProvisioned = types.NewBool(false)
+			backing.EagerlyScrub = types.NewBool(true)
+		case "thin":
+			backing.ThinProvisioned = types.NewBool(true)
+			backing.EagerlyScrub = types.NewBool(false)
+		default: // lazy
+			backing.ThinProvisioned = types.NewBool(false)
+			backing.EagerlyScrub = types.NewBool(false)
+		}
+	}
+
+	fileOperation := types.VirtualDeviceConfigSpecFileOperationCreate
+	if _, err := dss.Stat(ctx, dsFile); err == nil {
+		// The VMDK already exists on the datastore; attach it as-is.
+		fileOperation = ""
+	}
+
+	task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation:     types.VirtualDeviceConfigSpecOperationAdd,
+				FileOperation: fileOperation,
+				Device:        disk,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("attaching persistent disk %q: %w", spec.Name, err)
+	}
+
+	return task.Wait(ctx)
+}
+
+// AttachVMDK uploads a pre-built localPath VMDK to vmName's folder on dss,
+// reporting progress through onProgress (which may be nil), and attaches it
+// as-is (its existing capacity and provisioning are left untouched). Callers
+// must not separately upload localPath beforehand.
+func (c *Client) AttachVMDK(ctx context.Context, vm *object.VirtualMachine, dss *object.Datastore, vmName, controllerType, localPath string, onProgress ProgressFunc) error {
+	if err := c.uploadFile(ctx, dss, vmName, localPath, onProgress); err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(baseName(localPath), ".vmdk")
+
+	return c.AttachDisk(ctx, vm, dss, vmName, controllerType, DiskSpec{Name: name})
+}
@@ -0,0 +1,25 @@
+package stevedor
+
+import "testing"
+
+func TestValidControllerType(t *testing.T) {
+	tests := []struct {
+		controller string
+		want       bool
+	}{
+		{"pvscsi", true},
+		{"lsilogic", true},
+		{"lsilogic-sas", true},
+		{"buslogic", true},
+		{"ide", true},
+		{"scsi", false},
+		{"", false},
+		{"PVSCSI", false},
+	}
+
+	for _, tt := range tests {
+		if got := validControllerType(tt.controller); got != tt.want {
+			t.Errorf("validControllerType(%q) = %v, want %v", tt.controller, got, tt.want)
+		}
+	}
+}
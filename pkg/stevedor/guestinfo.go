@@ -0,0 +1,61 @@
+package stevedor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// buildExtraConfig turns a set of guestinfo key/value pairs and the optional
+// cloud-init userdata/metadata files into the ExtraConfig entries that
+// LinuxKit and cloud-init read out of guestinfo.* on first boot.
+func buildExtraConfig(guestinfo map[string]string, userdataPath, metadataPath string) ([]types.BaseOptionValue, error) {
+	var extra []types.BaseOptionValue
+
+	for key, value := range guestinfo {
+		extra = append(extra, &types.OptionValue{
+			Key:   fmt.Sprintf("guestinfo.%s", key),
+			Value: value,
+		})
+	}
+
+	if userdataPath != "" {
+		opts, err := guestinfoFileOptions("userdata", userdataPath)
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, opts...)
+	}
+
+	if metadataPath != "" {
+		opts, err := guestinfoFileOptions("metadata", metadataPath)
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, opts...)
+	}
+
+	return extra, nil
+}
+
+func guestinfoFileOptions(name, path string) ([]types.BaseOptionValue, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s file %q: %w", name, path, err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(raw)
+
+	return []types.BaseOptionValue{
+		&types.OptionValue{
+			Key:   fmt.Sprintf("guestinfo.%s", name),
+			Value: encoded,
+		},
+		&types.OptionValue{
+			Key:   fmt.Sprintf("guestinfo.%s.encoding", name),
+			Value: "base64",
+		},
+	}, nil
+}
@@ -0,0 +1,86 @@
+package stevedor
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func optionValue(extra []types.BaseOptionValue, key string) (string, bool) {
+	for _, e := range extra {
+		ov := e.GetOptionValue()
+		if ov.Key == key {
+			return ov.Value.(string), true
+		}
+	}
+	return "", false
+}
+
+func TestBuildExtraConfigGuestInfoOnly(t *testing.T) {
+	extra, err := buildExtraConfig(map[string]string{"hostname": "web01"}, "", "")
+	if err != nil {
+		t.Fatalf("buildExtraConfig() error = %v", err)
+	}
+
+	if v, ok := optionValue(extra, "guestinfo.hostname"); !ok || v != "web01" {
+		t.Fatalf("guestinfo.hostname = %q, %v, want web01, true", v, ok)
+	}
+}
+
+func TestBuildExtraConfigUserdataMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	userdataPath := filepath.Join(dir, "user-data")
+	if err := ioutil.WriteFile(userdataPath, []byte("#cloud-config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	metadataPath := filepath.Join(dir, "meta-data")
+	if err := ioutil.WriteFile(metadataPath, []byte("instance-id: abc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	extra, err := buildExtraConfig(nil, userdataPath, metadataPath)
+	if err != nil {
+		t.Fatalf("buildExtraConfig() error = %v", err)
+	}
+
+	for _, name := range []string{"userdata", "metadata"} {
+		if _, ok := optionValue(extra, "guestinfo."+name); !ok {
+			t.Fatalf("missing guestinfo.%s", name)
+		}
+		if enc, ok := optionValue(extra, "guestinfo."+name+".encoding"); !ok || enc != "base64" {
+			t.Fatalf("guestinfo.%s.encoding = %q, %v, want base64, true", name, enc, ok)
+		}
+	}
+}
+
+func TestBuildExtraConfigMissingUserdataFile(t *testing.T) {
+	_, err := buildExtraConfig(nil, "/nonexistent/user-data", "")
+	if err == nil {
+		t.Fatal("expected an error for a missing userdata file, got nil")
+	}
+}
+
+func TestGuestinfoFileOptionsEncodesBase64(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "user-data")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := guestinfoFileOptions("userdata", path)
+	if err != nil {
+		t.Fatalf("guestinfoFileOptions() error = %v", err)
+	}
+
+	v, ok := optionValue(opts, "guestinfo.userdata")
+	if !ok {
+		t.Fatal("missing guestinfo.userdata")
+	}
+	if v != "aGVsbG8=" {
+		t.Fatalf("guestinfo.userdata = %q, want aGVsbG8=", v)
+	}
+}
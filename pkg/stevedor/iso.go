@@ -0,0 +1,40 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+)
+
+// AttachISO uploads localPath to vmName's folder on dss, reporting progress
+// through onProgress (which may be nil), and attaches it as a CD-ROM on vm's
+// IDE controller. Callers must not separately upload localPath beforehand.
+func (c *Client) AttachISO(ctx context.Context, vm *object.VirtualMachine, dss *object.Datastore, vmName, localPath string, onProgress ProgressFunc) error {
+	if err := c.uploadFile(ctx, dss, vmName, localPath, onProgress); err != nil {
+		return err
+	}
+
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return fmt.Errorf("listing devices: %w", err)
+	}
+
+	ide, err := devices.FindIDEController("")
+	if err != nil {
+		return fmt.Errorf("finding IDE controller: %w", err)
+	}
+
+	cdrom, err := devices.CreateCdrom(ide)
+	if err != nil {
+		return fmt.Errorf("creating cdrom device: %w", err)
+	}
+
+	isoName := fmt.Sprintf("%s/%s", vmName, baseName(localPath))
+
+	if err := vm.AddDevice(ctx, devices.InsertIso(cdrom, dss.Path(isoName))); err != nil {
+		return fmt.Errorf("attaching ISO %q: %w", localPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/library"
+	vapifinder "github.com/vmware/govmomi/vapi/library/finder"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+)
+
+// LibraryDeploySpec describes a Content Library item to deploy in place of
+// the raw VMDK+ISO create path.
+type LibraryDeploySpec struct {
+	Library string
+	Item    string
+	Name    string
+	Folder  *object.Folder
+	Pool    *object.ResourcePool
+	Host    *object.HostSystem
+	DS      *object.Datastore
+}
+
+// DeployFromLibrary deploys spec.Name from a Content Library item, logging
+// in to the vAPI REST endpoint with the same credentials used for the SOAP
+// (govmomi) session.
+func (c *Client) DeployFromLibrary(ctx context.Context, spec LibraryDeploySpec) (*object.VirtualMachine, error) {
+	rc := rest.NewClient(c.vim.Client)
+	if err := rc.Login(ctx, c.url.User); err != nil {
+		return nil, fmt.Errorf("logging in to vAPI REST endpoint: %w", err)
+	}
+	defer rc.Logout(ctx)
+
+	finder := vapifinder.NewFinder(library.NewManager(rc))
+
+	results, err := finder.Find(ctx, fmt.Sprintf("%s/%s", spec.Library, spec.Item))
+	if err != nil {
+		return nil, fmt.Errorf("resolving library item %q in library %q: %w", spec.Item, spec.Library, err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no item named %q found in library %q", spec.Item, spec.Library)
+	}
+
+	item, ok := results[0].GetResult().(library.Item)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result resolving library item %q", spec.Item)
+	}
+
+	poolRef := spec.Pool.Reference()
+	hostRef := spec.Host.Reference()
+	dsRef := spec.DS.Reference()
+	folderRef := spec.Folder.Reference()
+
+	deploy := vcenter.Deploy{
+		DeploymentSpec: vcenter.DeploymentSpec{
+			Name:               spec.Name,
+			DefaultDatastoreID: dsRef.Value,
+			AcceptAllEULA:      true,
+		},
+		Target: vcenter.Target{
+			ResourcePoolID: poolRef.Value,
+			HostID:         hostRef.Value,
+			FolderID:       folderRef.Value,
+		},
+	}
+
+	ref, err := vcenter.NewManager(rc).DeployLibraryItem(ctx, item.ID, deploy)
+	if err != nil {
+		return nil, fmt.Errorf("deploying library item %q: %w", spec.Item, err)
+	}
+
+	return object.NewVirtualMachine(c.vim.Client, ref.Reference()), nil
+}
@@ -0,0 +1,55 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+// VMInfo is a summary of a Virtual Machine's identity, power state and
+// guest IP, as returned by ListVMs.
+type VMInfo struct {
+	Name       string
+	PowerState string
+	IPAddress  string
+}
+
+// ListVMs walks the datacenter's VM folder and returns a summary of every
+// Virtual Machine found.
+func (c *Client) ListVMs(ctx context.Context) ([]VMInfo, error) {
+	folders, err := c.Datacenter.Folders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding datacenter folders: %w", err)
+	}
+
+	v, err := view.NewManager(c.vim.Client).CreateContainerView(ctx, folders.VmFolder.Reference(), []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("creating container view: %w", err)
+	}
+	defer v.Destroy(ctx)
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"name", "runtime.powerState", "guest.ipAddress"}, &vms); err != nil {
+		return nil, fmt.Errorf("retrieving VM properties: %w", err)
+	}
+
+	infos := make([]VMInfo, 0, len(vms))
+	for _, vm := range vms {
+		info := VMInfo{
+			Name:       vm.Name,
+			PowerState: string(vm.Runtime.PowerState),
+		}
+
+		// Guest is nil until VMware Tools has reported in, which is the
+		// normal state immediately after stevedor create.
+		if vm.Guest != nil {
+			info.IPAddress = vm.Guest.IpAddress
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
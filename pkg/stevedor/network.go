@@ -0,0 +1,54 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// NetworkSpec describes a single NIC to attach: the network to join, the
+// Ethernet adapter type (vmxnet3, e1000, e1000e, pcnet32) and, optionally, a
+// manually assigned MAC address.
+type NetworkSpec struct {
+	Name        string
+	AdapterType string
+	MAC         string
+}
+
+// AttachNIC resolves spec.Name against the datacenter's network inventory and
+// attaches an Ethernet card of the requested type to vm.
+func (c *Client) AttachNIC(ctx context.Context, vm *object.VirtualMachine, spec NetworkSpec) error {
+	net, err := c.Finder.NetworkOrDefault(ctx, spec.Name)
+	if err != nil {
+		return fmt.Errorf("resolving network %q: %w", spec.Name, err)
+	}
+
+	adapterType := spec.AdapterType
+	if adapterType == "" {
+		adapterType = "vmxnet3"
+	}
+
+	backing, err := net.EthernetCardBackingInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("building ethernet card backing: %w", err)
+	}
+
+	netdev, err := object.EthernetCardTypes().CreateEthernetCard(adapterType, backing)
+	if err != nil {
+		return fmt.Errorf("creating %s ethernet card: %w", adapterType, err)
+	}
+
+	if spec.MAC != "" {
+		card := netdev.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		card.AddressType = string(types.VirtualEthernetCardMacTypeManual)
+		card.MacAddress = spec.MAC
+	}
+
+	if err := vm.AddDevice(ctx, netdev); err != nil {
+		return fmt.Errorf("adding NIC device: %w", err)
+	}
+
+	return nil
+}
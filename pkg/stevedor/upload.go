@@ -0,0 +1,137 @@
+package stevedor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+const (
+	uploadMaxAttempts  = 5
+	uploadInitialDelay = 2 * time.Second
+)
+
+// ProgressFunc is called as an upload proceeds, reporting an overall
+// completion percentage and a detail string (govmomi renders MB/s and an
+// ETA into it). Errors reported mid-transfer are passed through rather than
+// failing the whole report stream.
+type ProgressFunc func(label string, percentage float32, detail string, err error)
+
+type progressSink struct {
+	label string
+	fn    ProgressFunc
+}
+
+func (p progressSink) Sink() chan<- progress.Report {
+	ch := make(chan progress.Report)
+
+	go func() {
+		for report := range ch {
+			p.fn(p.label, report.Percentage(), report.Detail(), report.Error())
+		}
+	}()
+
+	return ch
+}
+
+// UploadFile uploads localPath into vmName's folder on dss, retrying
+// transient failures with exponential backoff and skipping the transfer
+// entirely if a copy of the same size, modified no earlier than the local
+// file, is already present. Use UploadFiles to
+// report progress or to upload more than one file concurrently.
+func (c *Client) UploadFile(ctx context.Context, dss *object.Datastore, vmName, localPath string) error {
+	return c.uploadFile(ctx, dss, vmName, localPath, nil)
+}
+
+// UploadFiles uploads each of localPaths into vmName's folder on dss
+// concurrently, reporting progress through onProgress (which may be nil).
+func (c *Client) UploadFiles(ctx context.Context, dss *object.Datastore, vmName string, localPaths []string, onProgress ProgressFunc) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(localPaths))
+
+	for i, localPath := range localPaths {
+		wg.Add(1)
+		go func(i int, localPath string) {
+			defer wg.Done()
+			errs[i] = c.uploadFile(ctx, dss, vmName, localPath, onProgress)
+		}(i, localPath)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) uploadFile(ctx context.Context, dss *object.Datastore, vmName, localPath string, onProgress ProgressFunc) error {
+	fileName := baseName(localPath)
+	dsFile := fmt.Sprintf("%s/%s", vmName, fileName)
+
+	fi, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("stating local file %q: %w", localPath, err)
+	}
+
+	if existing, err := dss.Stat(ctx, dsFile); err == nil {
+		info := existing.GetFileInfo()
+		sameSize := info.FileSize == fi.Size()
+		notModifiedSince := info.Modification != nil && !fi.ModTime().After(*info.Modification)
+		if sameSize && notModifiedSince {
+			return nil
+		}
+	}
+
+	dsurl := dss.NewURL(dsFile)
+
+	var lastErr error
+	delay := uploadInitialDelay
+
+	for attempt := 1; attempt <= uploadMaxAttempts; attempt++ {
+		p := soap.DefaultUpload
+		if onProgress != nil {
+			p.Progress = progressSink{label: fileName, fn: onProgress}
+		}
+
+		lastErr = c.vim.Client.UploadFile(ctx, localPath, dsurl, &p)
+		if lastErr == nil {
+			return nil
+		}
+
+		if !isTransientUploadError(lastErr) {
+			return fmt.Errorf("uploading %q: %w", localPath, lastErr)
+		}
+
+		if attempt < uploadMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return fmt.Errorf("uploading %q after %d attempts: %w", localPath, uploadMaxAttempts, lastErr)
+}
+
+// isTransientUploadError reports whether err looks like a transport-level
+// hiccup worth retrying. SOAP faults (bad credentials, permission denied,
+// malformed datastore paths) are permanent misconfigurations and should fail
+// fast rather than eat ~30s of backoff across 5 attempts.
+func isTransientUploadError(err error) bool {
+	if soap.IsSoapFault(err) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,8 @@
+package stevedor
+
+import "path"
+
+func baseName(p string) string {
+	_, name := path.Split(p)
+	return name
+}
@@ -0,0 +1,159 @@
+package stevedor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// VMConfig describes the shell of a Virtual Machine to create. Disks, NICs
+// and ISOs are attached afterwards via AttachDisk, AttachNIC and AttachISO.
+type VMConfig struct {
+	Name       string
+	GuestID    string
+	Datastore  *object.Datastore
+	Host       *object.HostSystem
+	Pool       *object.ResourcePool
+	NumCPUs    int32
+	MemoryMB   int64
+	Controller string // lsilogic, lsilogic-sas, buslogic, pvscsi or ide
+	GuestInfo  map[string]string
+	Userdata   string // path to a cloud-init userdata file
+	Metadata   string // path to a cloud-init metadata file
+}
+
+// CreateVM creates an empty Virtual Machine shell from cfg and waits for the
+// creation task to complete.
+func (c *Client) CreateVM(ctx context.Context, cfg VMConfig) (*object.VirtualMachine, error) {
+	if !validControllerType(cfg.Controller) {
+		return nil, fmt.Errorf("unknown controller type %q, expected lsilogic, lsilogic-sas, buslogic, pvscsi or ide", cfg.Controller)
+	}
+
+	extraConfig, err := buildExtraConfig(cfg.GuestInfo, cfg.Userdata, cfg.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := types.VirtualMachineConfigSpec{
+		Name:        cfg.Name,
+		GuestId:     cfg.GuestID,
+		Files:       &types.VirtualMachineFileInfo{VmPathName: fmt.Sprintf("[%s]", cfg.Datastore.Name())},
+		NumCPUs:     cfg.NumCPUs,
+		MemoryMB:    cfg.MemoryMB,
+		ExtraConfig: extraConfig,
+	}
+
+	if cfg.Controller != "ide" {
+		scsi, err := object.SCSIControllerTypes().CreateSCSIController(cfg.Controller)
+		if err != nil {
+			return nil, fmt.Errorf("creating %s controller: %w", cfg.Controller, err)
+		}
+
+		spec.DeviceChange = append(spec.DeviceChange, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationAdd,
+			Device:    scsi,
+		})
+	}
+
+	folders, err := c.Datacenter.Folders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding datacenter folders: %w", err)
+	}
+
+	task, err := folders.VmFolder.CreateVM(ctx, spec, cfg.Pool, cfg.Host)
+	if err != nil {
+		return nil, fmt.Errorf("creating VM %q: %w", cfg.Name, err)
+	}
+
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for VM %q creation: %w", cfg.Name, err)
+	}
+
+	return object.NewVirtualMachine(c.vim.Client, info.Result.(types.ManagedObjectReference)), nil
+}
+
+// DeleteVM powers off (if needed) and destroys vm along with its datastore
+// folder.
+func (c *Client) DeleteVM(ctx context.Context, vm *object.VirtualMachine) error {
+	task, err := vm.PowerOff(ctx)
+	if err != nil {
+		return fmt.Errorf("powering off VM: %w", err)
+	}
+	// Ignore the error: the VM may already be powered off.
+	task.Wait(ctx)
+
+	task, err = vm.Destroy(ctx)
+	if err != nil {
+		return fmt.Errorf("destroying VM: %w", err)
+	}
+
+	if err := task.Wait(ctx); err != nil {
+		return fmt.Errorf("waiting for VM destroy: %w", err)
+	}
+
+	return nil
+}
+
+// PowerState applies state ("on", "off" or "reset") to vm.
+func (c *Client) PowerState(ctx context.Context, vm *object.VirtualMachine, state string) error {
+	var task *object.Task
+	var err error
+
+	switch state {
+	case "on":
+		task, err = vm.PowerOn(ctx)
+	case "off":
+		task, err = vm.PowerOff(ctx)
+	case "reset":
+		task, err = vm.Reset(ctx)
+	default:
+		return fmt.Errorf("unknown power state %q, expected on, off or reset", state)
+	}
+
+	if err != nil {
+		return fmt.Errorf("changing power state to %q: %w", state, err)
+	}
+
+	if err := task.Wait(ctx); err != nil {
+		return fmt.Errorf("waiting for power state %q: %w", state, err)
+	}
+
+	return nil
+}
+
+// CloneVM clones src into a new VM named dstName, located via cfg.
+func (c *Client) CloneVM(ctx context.Context, src *object.VirtualMachine, dstName string, cfg VMConfig) (*object.VirtualMachine, error) {
+	folders, err := c.Datacenter.Folders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding datacenter folders: %w", err)
+	}
+
+	dsRef := cfg.Datastore.Reference()
+	poolRef := cfg.Pool.Reference()
+	hostRef := cfg.Host.Reference()
+
+	cloneSpec := types.VirtualMachineCloneSpec{
+		Location: types.VirtualMachineRelocateSpec{
+			Datastore: &dsRef,
+			Pool:      &poolRef,
+			Host:      &hostRef,
+		},
+		PowerOn:  false,
+		Template: false,
+	}
+
+	task, err := src.Clone(ctx, folders.VmFolder, dstName, cloneSpec)
+	if err != nil {
+		return nil, fmt.Errorf("cloning VM %q to %q: %w", src.Name(), dstName, err)
+	}
+
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for clone of %q: %w", src.Name(), err)
+	}
+
+	return object.NewVirtualMachine(c.vim.Client, info.Result.(types.ManagedObjectReference)), nil
+}